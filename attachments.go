@@ -0,0 +1,130 @@
+package poclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DownloadAttachment fetches the image attached to msg, if any, returning its
+// body and content type. The caller is responsible for closing the returned
+// ReadCloser.
+// https://pushover.net/api/client#download
+func (p Client) DownloadAttachment(msg Message) (io.ReadCloser, string, error) {
+	return p.DownloadAttachmentContext(context.Background(), msg)
+}
+
+// DownloadAttachmentContext behaves like DownloadAttachment but additionally
+// accepts a context.Context to control cancellation of the underlying HTTP
+// request (including retries). Unlike the other *Context methods, the
+// Client's per-request Timeout is not applied here since attachment sizes
+// can vary widely; use ctx to bound how long a download may take.
+func (p Client) DownloadAttachmentContext(ctx context.Context, msg Message) (io.ReadCloser, string, error) {
+	if !p.loggedIn {
+		return nil, "", ErrNotLoggedIn
+	}
+	if !p.registered {
+		return nil, "", ErrDeviceNotRegistered
+	}
+
+	requestURL := fmt.Sprintf("%s/1/messages/%d/download.json?secret=%s&device_id=%s",
+		p.baseURL, msg.RelativeID, p.user.Secret, p.device.ID)
+
+	resp, err := p.doStreamRequest(ctx, http.MethodGet, requestURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// DownloadIcon fetches the per-app icon for msg. The caller is responsible
+// for closing the returned ReadCloser.
+// https://pushover.net/api/client#icons
+func (p Client) DownloadIcon(msg Message) (io.ReadCloser, error) {
+	return p.DownloadIconContext(context.Background(), msg)
+}
+
+// DownloadIconContext behaves like DownloadIcon but additionally accepts a
+// context.Context to control cancellation of the underlying HTTP request
+// (including retries). As with DownloadAttachmentContext, the Client's
+// per-request Timeout is not applied here.
+func (p Client) DownloadIconContext(ctx context.Context, msg Message) (io.ReadCloser, error) {
+	requestURL := fmt.Sprintf("%s/icons/%s.png", p.baseURL, msg.IconID)
+
+	resp, err := p.doStreamRequest(ctx, http.MethodGet, requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// doStreamRequest is like doRequest but returns the raw *http.Response
+// instead of buffering its body, for endpoints whose response is consumed as
+// a stream (attachment/icon downloads). The caller must close resp.Body.
+func (p Client) doStreamRequest(ctx context.Context, method, requestURL string) (*http.Response, error) {
+	if p.rateLimiter != nil {
+		if err := p.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+
+	err := retryWithBackoff(ctx, p.retryPolicy, func() (bool, error) {
+		r, retryable, err := p.doStreamRequestOnce(ctx, method, requestURL)
+		if err == nil {
+			resp = r
+		}
+		return retryable, err
+	})
+
+	return resp, err
+}
+
+func (p Client) doStreamRequestOnce(ctx context.Context, method, requestURL string) (resp *http.Response, retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err = p.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+
+	if isRetryableStatus(resp.StatusCode) {
+		resp.Body.Close()
+		return nil, true, &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, false, parseDownloadError(resp)
+	}
+
+	return resp, false, nil
+}
+
+// parseDownloadError builds an APIError from a failed download response,
+// parsing Pushover's usual {status, errors} JSON body when present so
+// callers can errors.As it like every other failure path in the library.
+func parseDownloadError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &APIError{StatusCode: resp.StatusCode}
+	}
+
+	var reply struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return newListAPIError(resp.StatusCode, 0, []string{string(body)}, nil)
+	}
+
+	return newListAPIError(resp.StatusCode, reply.Status, reply.Errors, nil)
+}
@@ -0,0 +1,97 @@
+package poclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDownloadAttachmentAndIconAgainstMockServer(t *testing.T) {
+	var gotAttachmentPath, gotIconPath string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/messages/1/download.json", func(w http.ResponseWriter, r *http.Request) {
+		gotAttachmentPath = r.URL.Path
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("attachment-bytes"))
+	})
+	mux.HandleFunc("/icons/icon1.png", func(w http.ResponseWriter, r *http.Request) {
+		gotIconPath = r.URL.Path
+		w.Write([]byte("icon-bytes"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewWithOptions(ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	client.RestoreLogin("s1", "u1")
+	client.RestoreDevice("dev1")
+
+	body, contentType, err := client.DownloadAttachmentContext(context.Background(), Message{RelativeID: 1})
+	if err != nil {
+		t.Fatalf("DownloadAttachmentContext() error = %v", err)
+	}
+	defer body.Close()
+
+	if gotAttachmentPath != "/1/messages/1/download.json" {
+		t.Fatalf("attachment request path = %q, want %q", gotAttachmentPath, "/1/messages/1/download.json")
+	}
+	if contentType != "image/jpeg" {
+		t.Fatalf("DownloadAttachmentContext() contentType = %q, want %q", contentType, "image/jpeg")
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading attachment body: %v", err)
+	}
+	if string(data) != "attachment-bytes" {
+		t.Fatalf("attachment body = %q, want %q", data, "attachment-bytes")
+	}
+
+	iconBody, err := client.DownloadIconContext(context.Background(), Message{IconID: "icon1"})
+	if err != nil {
+		t.Fatalf("DownloadIconContext() error = %v", err)
+	}
+	defer iconBody.Close()
+
+	if gotIconPath != "/icons/icon1.png" {
+		t.Fatalf("icon request path = %q, want %q", gotIconPath, "/icons/icon1.png")
+	}
+}
+
+func TestDownloadAttachmentErrorAgainstMockServer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/messages/1/download.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":0,"errors":["message not found"]}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewWithOptions(ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	client.RestoreLogin("s1", "u1")
+	client.RestoreDevice("dev1")
+
+	_, _, err := client.DownloadAttachmentContext(context.Background(), Message{RelativeID: 1})
+	if err == nil {
+		t.Fatal("DownloadAttachmentContext() error = nil, want an error for a 404 response")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("DownloadAttachmentContext() error = %v, want an *APIError", err)
+	}
+	if len(apiErr.Fields["error"]) == 0 || apiErr.Fields["error"][0] != "message not found" {
+		t.Fatalf("APIError.Fields from DownloadAttachmentContext() = %+v, want the response body's error message", apiErr.Fields)
+	}
+}
@@ -0,0 +1,115 @@
+package poclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+const (
+	minReconnectDelay = 1 * time.Second
+	maxReconnectDelay = 5 * time.Minute
+)
+
+// OnMessage registers a handler invoked for every Message received over the
+// websocket, in addition to it being sent on the Messages channel. Calling
+// OnMessage again replaces the previous handler.
+func (p *Client) OnMessage(handler func(Message)) {
+	p.onMessage = handler
+}
+
+// OnKeepAlive registers a handler invoked for every keep-alive packet
+// received over the websocket. Calling OnKeepAlive again replaces the
+// previous handler.
+func (p *Client) OnKeepAlive(handler func()) {
+	p.onKeepAlive = handler
+}
+
+// OnReload registers a handler invoked when the API sends a reload frame.
+// Run performs the reconnect itself; this handler is purely informational.
+// Calling OnReload again replaces the previous handler.
+func (p *Client) OnReload(handler func()) {
+	p.onReload = handler
+}
+
+// OnError registers a handler invoked whenever a connection attempt inside
+// Run fails or an established connection drops. Calling OnError again
+// replaces the previous handler.
+func (p *Client) OnError(handler func(error)) {
+	p.onError = handler
+}
+
+// Run owns a reconnect loop around the websocket notification stream,
+// dispatching incoming messages and events to the handlers registered via
+// OnMessage, OnKeepAlive, OnReload and OnError. After a dropped connection it
+// reconnects with exponential backoff and jitter, capped at 5 minutes; the
+// backoff resets to its minimum after any keep-alive is received. Run
+// returns when ctx is cancelled, or when the API reports the device as
+// disabled (see ErrDeviceDisabled), which is not recoverable by reconnecting.
+func (p *Client) Run(ctx context.Context) error {
+	if !p.loggedIn {
+		return ErrNotLoggedIn
+	}
+	if !p.registered {
+		return ErrDeviceNotRegistered
+	}
+
+	delay := minReconnectDelay
+
+	for {
+		gotKeepAlive := false
+
+		dispatch := func(message string) (bool, error) {
+			reconnect, keepAlive, err := p.dispatchNotification(message)
+			if keepAlive {
+				gotKeepAlive = true
+			}
+			return reconnect, err
+		}
+
+		err := p.connectAndDispatch(ctx, dispatch)
+
+		if gotKeepAlive {
+			delay = minReconnectDelay
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			if p.onError != nil {
+				p.onError(err)
+			}
+			if errors.Is(err, ErrDeviceDisabled) {
+				return err
+			}
+		}
+
+		if !sleepWithJitter(ctx, &delay, maxReconnectDelay) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepWithJitter waits for a random duration between half of *delay and
+// *delay, doubles *delay for next time (capped at max), and returns false if
+// ctx is cancelled before the wait completes.
+func sleepWithJitter(ctx context.Context, delay *time.Duration, max time.Duration) bool {
+	jitter := time.Duration(rand.Int63n(int64(*delay) + 1))
+	wait := *delay/2 + jitter/2
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+	}
+
+	*delay *= 2
+	if *delay > max {
+		*delay = max
+	}
+
+	return true
+}
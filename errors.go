@@ -0,0 +1,78 @@
+package poclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors returned by Client methods. Use errors.Is to check for
+// these and errors.As with *APIError to inspect the full API response.
+var (
+	// ErrNotLoggedIn is returned when an operation requires a logged in user but Login hasn't succeeded yet.
+	ErrNotLoggedIn = errors.New("not logged in")
+	// ErrAlreadyLoggedIn is returned by Login when the Client is already logged in.
+	ErrAlreadyLoggedIn = errors.New("already logged in")
+	// ErrDeviceNotRegistered is returned when an operation requires a registered device but RegisterDevice hasn't succeeded yet.
+	ErrDeviceNotRegistered = errors.New("device not registered")
+	// ErrInvalidCredentials is returned by Login when the given email/password are rejected by the API.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrDeviceDisabled is returned when the API sends an "E" frame over the websocket,
+	// meaning the device was disabled or the user needs to log in again.
+	ErrDeviceDisabled = errors.New("device disabled or login required")
+)
+
+// APIError represents a failed Pushover API response. It carries the HTTP
+// status code, Pushover's own numeric status field and any per-field
+// validation messages returned by the API, and can wrap one of the sentinel
+// errors above so callers can use errors.Is for the common cases while still
+// having access to the full response via errors.As.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the value of Pushover's own "status" field (0 on failure).
+	Status int
+	// Fields maps a Pushover field name to the validation messages reported for it.
+	// Responses that don't report per-field errors use the key "error".
+	Fields map[string][]string
+	// Err is the sentinel error identifying the failure reason, if one applies.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pushover API error (http %d, status %d)", e.StatusCode, e.Status)
+
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %s", e.Err)
+	}
+
+	for field, messages := range e.Fields {
+		for _, msg := range messages {
+			fmt.Fprintf(&b, "; %s %s", field, msg)
+		}
+	}
+
+	return b.String()
+}
+
+// Unwrap allows errors.Is/errors.As to see through to the sentinel error, if any.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newFieldAPIError builds an APIError from a reply carrying per-field
+// validation messages, such as devicesReply.Errors.
+func newFieldAPIError(statusCode, status int, fields map[string][]string, err error) *APIError {
+	return &APIError{StatusCode: statusCode, Status: status, Fields: fields, Err: err}
+}
+
+// newListAPIError builds an APIError from a reply carrying a flat list of
+// error messages, such as loginReply.Errors or messagesReply.Errors.
+func newListAPIError(statusCode, status int, messages []string, err error) *APIError {
+	fields := map[string][]string{}
+	if len(messages) > 0 {
+		fields["error"] = messages
+	}
+	return newFieldAPIError(statusCode, status, fields, err)
+}
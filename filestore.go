@@ -0,0 +1,62 @@
+package poclient
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// FileSessionStore is a SessionStore backed by a single JSON file, written
+// with 0600 permissions since it contains credentials. The file is created
+// on the first Save and does not need to exist beforehand.
+type FileSessionStore struct {
+	Path string
+}
+
+// NewFileSessionStore creates a FileSessionStore backed by the file at path.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{Path: path}
+}
+
+type fileSessionData struct {
+	UserID   string `json:"user_id"`
+	Secret   string `json:"secret"`
+	DeviceID string `json:"device_id"`
+}
+
+// Load implements SessionStore. It returns three empty strings and a nil
+// error if the file doesn't exist yet.
+func (s *FileSessionStore) Load() (userID string, secret string, deviceID string, err error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return "", "", "", nil
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var session fileSessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return "", "", "", err
+	}
+
+	return session.UserID, session.Secret, session.DeviceID, nil
+}
+
+// Save implements SessionStore.
+func (s *FileSessionStore) Save(userID string, secret string, deviceID string) error {
+	data, err := json.Marshal(fileSessionData{UserID: userID, Secret: secret, DeviceID: deviceID})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// Clear implements SessionStore. It is a no-op if the file doesn't exist.
+func (s *FileSessionStore) Clear() error {
+	err := os.Remove(s.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
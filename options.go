@@ -0,0 +1,53 @@
+package poclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// defaultBaseURL is the Pushover API endpoint used unless ClientOptions.BaseURL
+// overrides it.
+const defaultBaseURL = "https://api.pushover.net"
+
+// RetryPolicy controls how transient errors from the Pushover API are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts made after the first one fails.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry; it doubles after each further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff delay.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries transient failures up to 3 times with exponential
+// backoff starting at 500ms and capped at 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	}
+}
+
+// ClientOptions configures a Client created with NewWithOptions. The zero
+// value uses http.DefaultClient's settings, the default Pushover base URL and
+// DefaultRetryPolicy().
+type ClientOptions struct {
+	// HTTPClient is used for all outbound requests. If nil, a new http.Client is created.
+	HTTPClient *http.Client
+	// Transport, if set, is installed as the RoundTripper of HTTPClient. Useful for
+	// injecting mocks or middleware (logging, metrics) in tests or services.
+	Transport http.RoundTripper
+	// BaseURL overrides the Pushover API base URL, mainly for testing against a mock server.
+	BaseURL string
+	// Timeout bounds each individual HTTP request (including retries, each attempt gets
+	// its own timeout). Zero means no additional timeout is applied beyond the context.
+	// Attachment and icon downloads are not bounded by Timeout.
+	Timeout time.Duration
+	// RetryPolicy controls retries of transient 5xx/network errors. If nil, DefaultRetryPolicy() is used.
+	RetryPolicy *RetryPolicy
+	// RateLimit, if set, bounds the rate of HTTP API calls with a token-bucket
+	// limiter, to stay within Pushover's documented per-application rate limits.
+	// Nil (the default) applies no limiting.
+	RateLimit *RateLimit
+}
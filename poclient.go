@@ -1,6 +1,7 @@
 package poclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,27 +9,86 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // Client represents the main Pushover Client.
 // The Messages channel works in conjunction with ListenForNotifications,
-// which pushes incoming Messages into this channel.
+// which pushes incoming Messages into this channel. Run is a higher-level
+// alternative that additionally dispatches to the handlers registered via
+// OnMessage, OnKeepAlive, OnReload and OnError; it still sends every Message
+// into the Messages channel as well, and blocks while that channel is full.
 type Client struct {
 	loggedIn   bool
 	registered bool
 	user       user
 	device     device
 	Messages   chan Message
+
+	httpClient  *http.Client
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	rateLimiter *tokenBucket
+	store       SessionStore
+
+	wsConn *websocket.Conn
+
+	onMessage   func(Message)
+	onKeepAlive func()
+	onReload    func()
+	onError     func(error)
 }
 
 // New creates a new POClient with default values
 func New() *Client {
+	return NewWithOptions(ClientOptions{})
+}
+
+// NewWithOptions creates a new POClient using the given ClientOptions,
+// allowing a custom http.Client/RoundTripper, base URL override (for testing
+// against a mock server), retry/backoff policy and rate limit to be supplied.
+func NewWithOptions(opts ClientOptions) *Client {
+	var httpClient *http.Client
+	if opts.HTTPClient != nil {
+		// Clone rather than mutate the caller's http.Client, since it may be
+		// shared with other Clients or reused across tests.
+		clone := *opts.HTTPClient
+		httpClient = &clone
+	} else {
+		httpClient = &http.Client{}
+	}
+	if opts.Transport != nil {
+		httpClient.Transport = opts.Transport
+	}
+
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	retryPolicy := DefaultRetryPolicy()
+	if opts.RetryPolicy != nil {
+		retryPolicy = *opts.RetryPolicy
+	}
+
+	var rateLimiter *tokenBucket
+	if opts.RateLimit != nil && opts.RateLimit.RequestsPerSecond > 0 {
+		rateLimiter = newTokenBucket(*opts.RateLimit)
+	}
+
 	return &Client{
-		loggedIn:   false,
-		registered: false,
-		user:       user{},
-		device:     device{},
-		Messages:   make(chan Message, 32),
+		loggedIn:    false,
+		registered:  false,
+		user:        user{},
+		device:      device{},
+		Messages:    make(chan Message, 32),
+		httpClient:  httpClient,
+		baseURL:     baseURL,
+		timeout:     opts.Timeout,
+		retryPolicy: retryPolicy,
+		rateLimiter: rateLimiter,
 	}
 }
 
@@ -65,8 +125,15 @@ func (p *Client) GetStatus() (bool, bool) {
 // After successfully registering the device you should retrieve the device_id by calling Device()
 // and store it in a safe place.
 func (p *Client) RegisterDevice(name string) error {
+	return p.RegisterDeviceContext(context.Background(), name)
+}
+
+// RegisterDeviceContext behaves like RegisterDevice but additionally accepts
+// a context.Context to control cancellation and deadlines of the underlying
+// HTTP request (including retries).
+func (p *Client) RegisterDeviceContext(ctx context.Context, name string) error {
 	if !p.loggedIn {
-		return errors.New("Not logged in")
+		return ErrNotLoggedIn
 	}
 	if p.registered {
 		return errors.New("Already registered")
@@ -75,62 +142,85 @@ func (p *Client) RegisterDevice(name string) error {
 		return errors.New("Name is too long")
 	}
 
-	resp, err := http.PostForm("https://api.pushover.net/1/devices.json", url.Values{"secret": {p.user.Secret}, "name": {name}, "os": {"O"}})
-
+	data, statusCode, err := p.doRequest(ctx, http.MethodPost, p.baseURL+"/1/devices.json",
+		url.Values{"secret": {p.user.Secret}, "name": {name}, "os": {"O"}})
 	if err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
-
 	reply := devicesReply{}
-	err = json.NewDecoder(resp.Body).Decode(&reply)
-
-	if err != nil {
+	if err := json.Unmarshal(data, &reply); err != nil {
 		return err
 	}
 
 	if reply.Status == 0 {
-		errorMessage := ""
-		for errorType, messages := range reply.Errors {
-			for _, errMsg := range messages {
-				errorMessage += fmt.Sprintf("%s %s, ", errorType, errMsg)
-			}
-		}
-		return errors.New(errorMessage)
+		return newFieldAPIError(statusCode, reply.Status, reply.Errors, nil)
 	}
 
 	p.device.ID = reply.Deviceid
 	p.registered = true
 
-	return nil
+	return p.persistSession()
 }
 
 // Login retrieves user id and user secret.
 // After successfully logging, you should retrieve the user id and secret by calling User() and store
 // them somewhere safe.
+// If the account has two-factor authentication enabled, Login returns a
+// *Missing2FAError; prompt the user for their TOTP code and call LoginWith2FA
+// to complete the login.
 func (p *Client) Login(email string, password string) error {
+	return p.LoginContext(context.Background(), email, password)
+}
+
+// LoginContext behaves like Login but additionally accepts a context.Context
+// to control cancellation and deadlines of the underlying HTTP request
+// (including retries).
+func (p *Client) LoginContext(ctx context.Context, email string, password string) error {
+	if p.loggedIn {
+		return ErrAlreadyLoggedIn
+	}
+
+	return p.login(ctx, url.Values{"email": {email}, "password": {password}})
+}
+
+// LoginWith2FA completes a login that was interrupted by a *Missing2FAError
+// from Login, resending the credentials together with the TOTP code from the
+// user's authenticator app.
+func (p *Client) LoginWith2FA(email string, password string, otp string) error {
+	return p.LoginWith2FAContext(context.Background(), email, password, otp)
+}
+
+// LoginWith2FAContext behaves like LoginWith2FA but additionally accepts a
+// context.Context to control cancellation and deadlines of the underlying
+// HTTP request (including retries).
+func (p *Client) LoginWith2FAContext(ctx context.Context, email string, password string, otp string) error {
 	if p.loggedIn {
-		return errors.New("Already logged in")
+		return ErrAlreadyLoggedIn
 	}
 
-	resp, err := http.PostForm("https://api.pushover.net/1/users/login.json", url.Values{"email": {email}, "password": {password}})
+	return p.login(ctx, url.Values{"email": {email}, "password": {password}, "twofa": {otp}})
+}
 
+// login posts the given login form and applies the resulting session state.
+// It is shared by Login and LoginWith2FA, which only differ in whether the
+// "twofa" field is present in form.
+func (p *Client) login(ctx context.Context, form url.Values) error {
+	data, statusCode, err := p.doRequest(ctx, http.MethodPost, p.baseURL+"/1/users/login.json", form)
 	if err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
-
 	reply := loginReply{}
-	err = json.NewDecoder(resp.Body).Decode(&reply)
-
-	if err != nil {
+	if err := json.Unmarshal(data, &reply); err != nil {
 		return err
 	}
 
 	if reply.Status == 0 {
-		return errors.New(reply.Errors[0])
+		if statusCode == http.StatusPreconditionFailed {
+			return &Missing2FAError{}
+		}
+		return newListAPIError(statusCode, reply.Status, reply.Errors, ErrInvalidCredentials)
 	}
 
 	p.user.Secret = reply.Secret
@@ -138,40 +228,51 @@ func (p *Client) Login(email string, password string) error {
 	p.loggedIn = true
 	p.registered = false
 
-	return nil
+	return p.persistSession()
 }
 
 // GetMessages retrieves all new messages from the API.
 // Usually you call DeleteOldMessages right afterwards to clear all pending notifications
-func (p Client) GetMessages() ([]Message, error) {
+func (p *Client) GetMessages() ([]Message, error) {
+	return p.GetMessagesContext(context.Background())
+}
+
+// GetMessagesContext behaves like GetMessages but additionally accepts a
+// context.Context to control cancellation and deadlines of the underlying
+// HTTP request (including retries).
+//
+// GetMessagesContext has a pointer receiver (rather than Client's usual value
+// receiver for read-only calls) because each returned Message keeps a
+// reference to this Client for Message.Acknowledge; that reference must be
+// the live Client, not a frozen copy, so it keeps working after a later
+// re-login rotates the session secret.
+func (p *Client) GetMessagesContext(ctx context.Context) ([]Message, error) {
 	if !p.loggedIn {
-		return nil, errors.New("Not logged in")
+		return nil, ErrNotLoggedIn
 	}
 	if !p.registered {
-		return nil, errors.New("Device not registered")
+		return nil, ErrDeviceNotRegistered
 	}
 
-	resp, err := http.Get(fmt.Sprintf("https://api.pushover.net/1/messages.json?secret=%s&device_id=%s", p.user.Secret, p.device.ID))
-
+	data, statusCode, err := p.doRequest(ctx, http.MethodGet,
+		fmt.Sprintf("%s/1/messages.json?secret=%s&device_id=%s", p.baseURL, p.user.Secret, p.device.ID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
 	reply := messagesReply{}
-	err = json.NewDecoder(resp.Body).Decode(&reply)
-
-	if err != nil {
+	if err := json.Unmarshal(data, &reply); err != nil {
 		return nil, err
 	}
 
 	if reply.Status != 1 {
-		return reply.Messages, errors.New("Getting messages led to a status != 1")
+		return reply.Messages, newListAPIError(statusCode, reply.Status, reply.Errors, nil)
 	}
 
 	//parse all timestamps into time.Time
 	for i, msg := range reply.Messages {
 		reply.Messages[i].Date = time.Unix(msg.Timestamp, 0)
+		reply.Messages[i].client = p
 	}
 
 	return reply.Messages, nil
@@ -181,25 +282,28 @@ func (p Client) GetMessages() ([]Message, error) {
 // read which means they will not be transmitted again by the API
 // https://pushover.net/api/client#delete
 func (p Client) DeleteMessagesByID(highestID int) error {
-	resp, err := http.PostForm(
-		fmt.Sprintf("https://api.pushover.net/1/devices/%s/update_highest_message.json", p.device.ID),
+	return p.DeleteMessagesByIDContext(context.Background(), highestID)
+}
+
+// DeleteMessagesByIDContext behaves like DeleteMessagesByID but additionally
+// accepts a context.Context to control cancellation and deadlines of the
+// underlying HTTP request (including retries).
+func (p Client) DeleteMessagesByIDContext(ctx context.Context, highestID int) error {
+	data, statusCode, err := p.doRequest(ctx, http.MethodPost,
+		fmt.Sprintf("%s/1/devices/%s/update_highest_message.json", p.baseURL, p.device.ID),
 		url.Values{"secret": {p.user.Secret}, "message": {strconv.Itoa(highestID)}},
 	)
-
 	if err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
 	reply := deleteOldMessagesReply{}
-	err = json.NewDecoder(resp.Body).Decode(&reply)
-
-	if err != nil {
+	if err := json.Unmarshal(data, &reply); err != nil {
 		return err
 	}
 
 	if reply.Status == 0 {
-		return errors.New(reply.Errors[0])
+		return newListAPIError(statusCode, reply.Status, reply.Errors, nil)
 	}
 
 	return nil
@@ -0,0 +1,49 @@
+package poclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginContextAndGetMessagesContextAgainstMockServer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/users/login.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(loginReply{Status: 1, Userid: "u1", Secret: "s1"})
+	})
+	mux.HandleFunc("/1/messages.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(messagesReply{
+			Status:   1,
+			Messages: []Message{{RelativeID: 1, Text: "hello", Timestamp: 1000}},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewWithOptions(ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+
+	if err := client.LoginContext(context.Background(), "user@example.com", "hunter2"); err != nil {
+		t.Fatalf("LoginContext() error = %v", err)
+	}
+
+	userID, secret := client.User()
+	if userID != "u1" || secret != "s1" {
+		t.Fatalf("User() = (%q, %q), want (%q, %q)", userID, secret, "u1", "s1")
+	}
+
+	client.RestoreDevice("dev1")
+
+	messages, err := client.GetMessagesContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetMessagesContext() error = %v", err)
+	}
+	if len(messages) != 1 || messages[0].Text != "hello" {
+		t.Fatalf("GetMessagesContext() = %+v, want one message with text %q", messages, "hello")
+	}
+}
@@ -0,0 +1,82 @@
+package poclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a token-bucket limiter applied to every HTTP API call
+// a Client makes, so a caller issuing many requests in a burst doesn't run
+// into Pushover's documented per-application rate limits. Each call takes one
+// token before its first attempt; retries of that call take no further tokens.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate at which tokens are refilled.
+	RequestsPerSecond float64
+	// Burst is the maximum number of requests allowed without waiting. Values <= 0 are treated as 1.
+	Burst int
+}
+
+// tokenBucket is a minimal token-bucket rate limiter, implemented in-house so
+// Client doesn't need an external dependency just for this.
+type tokenBucket struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:       limit.RequestsPerSecond,
+		burst:      burst,
+		tokens:     burst,
+		lastRefill: time.Now(),
+	}
+}
+
+// wait blocks until a token is available, or returns ctx.Err() if ctx is
+// done first.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		wait := b.reserve()
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve refills the bucket, takes a token if one is available, and
+// otherwise reports how long the caller should wait before trying again.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.lastRefill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
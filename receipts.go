@@ -0,0 +1,83 @@
+package poclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type receiptActionReply struct {
+	Status int      `json:"status"`
+	Errors []string `json:"errors"`
+}
+
+// AcknowledgeReceipt acknowledges an emergency-priority (priority 2) message
+// identified by receipt, stopping the API from continuing to re-alert about it.
+// https://pushover.net/api/client#receipt
+func (p Client) AcknowledgeReceipt(receipt string) error {
+	return p.AcknowledgeReceiptContext(context.Background(), receipt)
+}
+
+// AcknowledgeReceiptContext behaves like AcknowledgeReceipt but additionally
+// accepts a context.Context to control cancellation and deadlines of the
+// underlying HTTP request (including retries).
+func (p Client) AcknowledgeReceiptContext(ctx context.Context, receipt string) error {
+	return p.doReceiptAction(ctx, receipt, "acknowledge")
+}
+
+// CancelReceipt stops an emergency-priority (priority 2) message identified
+// by receipt from continuing to re-alert.
+// https://pushover.net/api/client#receipt
+func (p Client) CancelReceipt(receipt string) error {
+	return p.CancelReceiptContext(context.Background(), receipt)
+}
+
+// CancelReceiptContext behaves like CancelReceipt but additionally accepts a
+// context.Context to control cancellation and deadlines of the underlying
+// HTTP request (including retries).
+func (p Client) CancelReceiptContext(ctx context.Context, receipt string) error {
+	return p.doReceiptAction(ctx, receipt, "cancel")
+}
+
+func (p Client) doReceiptAction(ctx context.Context, receipt string, action string) error {
+	if !p.loggedIn {
+		return ErrNotLoggedIn
+	}
+
+	data, statusCode, err := p.doRequest(ctx, http.MethodPost,
+		fmt.Sprintf("%s/1/receipts/%s/%s.json", p.baseURL, receipt, action),
+		url.Values{"secret": {p.user.Secret}},
+	)
+	if err != nil {
+		return err
+	}
+
+	reply := receiptActionReply{}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return err
+	}
+
+	if reply.Status == 0 {
+		return newListAPIError(statusCode, reply.Status, reply.Errors, nil)
+	}
+
+	return nil
+}
+
+// Acknowledge acknowledges this message's receipt, stopping the API from
+// continuing to re-alert about it if it was sent with emergency (priority 2)
+// priority. It is a convenience wrapper around Client.AcknowledgeReceiptContext
+// for messages obtained via GetMessages, Run or ListenForNotifications.
+func (m Message) Acknowledge(ctx context.Context) error {
+	if m.client == nil {
+		return errors.New("message was not obtained from a Client")
+	}
+	if m.ReceiptCode == "" {
+		return errors.New("message has no receipt")
+	}
+
+	return m.client.AcknowledgeReceiptContext(ctx, m.ReceiptCode)
+}
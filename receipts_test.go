@@ -0,0 +1,74 @@
+package poclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAcknowledgeAndCancelReceiptAgainstMockServer(t *testing.T) {
+	var gotAcknowledgePath, gotCancelPath string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/receipts/r1/acknowledge.json", func(w http.ResponseWriter, r *http.Request) {
+		gotAcknowledgePath = r.URL.Path
+		json.NewEncoder(w).Encode(receiptActionReply{Status: 1})
+	})
+	mux.HandleFunc("/1/receipts/r1/cancel.json", func(w http.ResponseWriter, r *http.Request) {
+		gotCancelPath = r.URL.Path
+		json.NewEncoder(w).Encode(receiptActionReply{Status: 1})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewWithOptions(ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	client.RestoreLogin("s1", "u1")
+
+	if err := client.AcknowledgeReceiptContext(context.Background(), "r1"); err != nil {
+		t.Fatalf("AcknowledgeReceiptContext() error = %v", err)
+	}
+	if gotAcknowledgePath != "/1/receipts/r1/acknowledge.json" {
+		t.Fatalf("acknowledge request path = %q, want %q", gotAcknowledgePath, "/1/receipts/r1/acknowledge.json")
+	}
+
+	if err := client.CancelReceiptContext(context.Background(), "r1"); err != nil {
+		t.Fatalf("CancelReceiptContext() error = %v", err)
+	}
+	if gotCancelPath != "/1/receipts/r1/cancel.json" {
+		t.Fatalf("cancel request path = %q, want %q", gotCancelPath, "/1/receipts/r1/cancel.json")
+	}
+}
+
+func TestMessageAcknowledgeAgainstMockServer(t *testing.T) {
+	var acknowledged bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/receipts/r1/acknowledge.json", func(w http.ResponseWriter, r *http.Request) {
+		acknowledged = true
+		json.NewEncoder(w).Encode(receiptActionReply{Status: 1})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewWithOptions(ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	client.RestoreLogin("s1", "u1")
+
+	msg := Message{ReceiptCode: "r1", client: client}
+
+	if err := msg.Acknowledge(context.Background()); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	if !acknowledged {
+		t.Fatal("Acknowledge() did not reach the mock server's acknowledge endpoint")
+	}
+}
@@ -0,0 +1,145 @@
+package poclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// isRetryableStatus reports whether an HTTP status code represents a transient
+// server-side failure worth retrying.
+func isRetryableStatus(code int) bool {
+	return code >= 500 && code <= 599
+}
+
+// doRequest performs method against requestURL, rate-limiting and retrying
+// transient network errors and 5xx responses according to the Client's
+// RateLimit and RetryPolicy. form may be nil for requests without a body.
+// The raw response body and HTTP status code are returned on success.
+func (p *Client) doRequest(ctx context.Context, method, requestURL string, form url.Values) ([]byte, int, error) {
+	if p.rateLimiter != nil {
+		if err := p.rateLimiter.wait(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var data []byte
+	var statusCode int
+
+	err := retryWithBackoff(ctx, p.retryPolicy, func() (bool, error) {
+		d, sc, retryable, err := p.doRequestOnce(ctx, method, requestURL, form)
+		if err == nil {
+			data, statusCode = d, sc
+		}
+		return retryable, err
+	})
+
+	return data, statusCode, err
+}
+
+// doRequestOnce performs a single attempt of method against requestURL and
+// reports whether the failure (if any) is worth retrying.
+func (p *Client) doRequestOnce(ctx context.Context, method, requestURL string, form url.Values) (respBody []byte, statusCode int, retryable bool, err error) {
+	reqCtx := ctx
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	var bodyReader io.Reader
+	if form != nil {
+		bodyReader = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, method, requestURL, bodyReader)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		// Don't retry once the caller's own context has been cancelled or has expired.
+		return nil, 0, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	if isRetryableStatus(resp.StatusCode) {
+		return nil, 0, true, &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	return data, resp.StatusCode, false, nil
+}
+
+// httpStatusError is returned internally when a request fails with a
+// retryable HTTP status; it is never surfaced to callers of doRequest.
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("pushover API returned status %d", e.StatusCode)
+}
+
+// retryWithBackoff runs attempt according to policy, retrying as long as
+// attempt reports retryable == true and the retry budget isn't exhausted.
+// It is shared by doRequest and doStreamRequest so the two don't each carry
+// their own copy of the attempt/backoff loop. A retryable HTTP status that
+// still fails on the last attempt is returned as an *APIError.
+func retryWithBackoff(ctx context.Context, policy RetryPolicy, attempt func() (retryable bool, err error)) error {
+	delay := policy.BaseDelay
+
+	var lastErr error
+
+	for i := 0; i <= policy.MaxRetries; i++ {
+		retryable, err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !retryable || i == policy.MaxRetries {
+			var statusErr *httpStatusError
+			if errors.As(err, &statusErr) {
+				return &APIError{StatusCode: statusErr.StatusCode}
+			}
+			return err
+		}
+		if !sleepBackoff(ctx, &delay, policy.MaxDelay) {
+			return ctx.Err()
+		}
+	}
+
+	return lastErr
+}
+
+// sleepBackoff waits for *delay, doubling it (capped at max) for the next
+// call, and returns false if ctx is cancelled before the wait completes.
+func sleepBackoff(ctx context.Context, delay *time.Duration, max time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(*delay):
+	}
+
+	*delay *= 2
+	if *delay > max {
+		*delay = max
+	}
+
+	return true
+}
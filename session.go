@@ -0,0 +1,86 @@
+package poclient
+
+// SessionStore persists a Client's session (user ID, user secret and device
+// ID) across restarts. Load is called once when the Client is created via
+// NewClientWithStore, and Save is called after every successful Login,
+// LoginWith2FA and RegisterDevice.
+type SessionStore interface {
+	// Load retrieves a previously saved session. If no session has been
+	// saved yet, it returns three empty strings and a nil error.
+	Load() (userID string, secret string, deviceID string, err error)
+	// Save persists the current session.
+	Save(userID string, secret string, deviceID string) error
+	// Clear removes any persisted session.
+	Clear() error
+}
+
+// NewClientWithStore creates a Client backed by store, restoring a
+// previously saved session (if any) and persisting the session again after
+// every successful Login, LoginWith2FA and RegisterDevice.
+func NewClientWithStore(store SessionStore) (*Client, error) {
+	return NewClientWithStoreAndOptions(store, ClientOptions{})
+}
+
+// NewClientWithStoreAndOptions behaves like NewClientWithStore but
+// additionally accepts ClientOptions, as NewWithOptions does.
+func NewClientWithStoreAndOptions(store SessionStore, opts ClientOptions) (*Client, error) {
+	p := NewWithOptions(opts)
+	p.store = store
+
+	userID, secret, deviceID, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if secret != "" {
+		p.RestoreLogin(secret, userID)
+	}
+	if deviceID != "" {
+		p.RestoreDevice(deviceID)
+	}
+
+	return p, nil
+}
+
+// persistSession saves the current session if the Client was created with a
+// SessionStore. It is a no-op otherwise.
+func (p *Client) persistSession() error {
+	if p.store == nil {
+		return nil
+	}
+	return p.store.Save(p.user.ID, p.user.Secret, p.device.ID)
+}
+
+// MemorySessionStore is a SessionStore that keeps the session in memory
+// only, primarily useful in tests where persisting to disk is undesirable.
+type MemorySessionStore struct {
+	userID   string
+	secret   string
+	deviceID string
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{}
+}
+
+// Load implements SessionStore.
+func (s *MemorySessionStore) Load() (userID string, secret string, deviceID string, err error) {
+	return s.userID, s.secret, s.deviceID, nil
+}
+
+// Save implements SessionStore.
+func (s *MemorySessionStore) Save(userID string, secret string, deviceID string) error {
+	s.userID = userID
+	s.secret = secret
+	s.deviceID = deviceID
+	return nil
+}
+
+// Clear implements SessionStore.
+func (s *MemorySessionStore) Clear() error {
+	s.userID = ""
+	s.secret = ""
+	s.deviceID = ""
+	return nil
+}
@@ -0,0 +1,99 @@
+package poclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientWithStoreAndOptionsRoundTrip(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/users/login.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(loginReply{Status: 1, Userid: "u1", Secret: "s1"})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "session.json"))
+
+	client, err := NewClientWithStoreAndOptions(store, ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithStoreAndOptions() error = %v", err)
+	}
+
+	if err := client.LoginContext(context.Background(), "user@example.com", "hunter2"); err != nil {
+		t.Fatalf("LoginContext() error = %v", err)
+	}
+
+	info, err := os.Stat(store.Path)
+	if err != nil {
+		t.Fatalf("Stat(%q) error = %v", store.Path, err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("session file permissions = %v, want 0600", perm)
+	}
+
+	restored, err := NewClientWithStoreAndOptions(store, ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithStoreAndOptions() error = %v", err)
+	}
+
+	loggedIn, _ := restored.GetStatus()
+	if !loggedIn {
+		t.Fatalf("GetStatus() loggedIn = false, want true after restoring a saved session")
+	}
+
+	userID, secret := restored.User()
+	if userID != "u1" || secret != "s1" {
+		t.Fatalf("User() = (%q, %q), want (%q, %q)", userID, secret, "u1", "s1")
+	}
+}
+
+func TestMemorySessionStoreRoundTrip(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/users/login.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(loginReply{Status: 1, Userid: "u1", Secret: "s1"})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := NewMemorySessionStore()
+
+	client, err := NewClientWithStoreAndOptions(store, ClientOptions{
+		BaseURL:   server.URL,
+		Transport: http.DefaultTransport,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithStoreAndOptions() error = %v", err)
+	}
+
+	if err := client.LoginContext(context.Background(), "user@example.com", "hunter2"); err != nil {
+		t.Fatalf("LoginContext() error = %v", err)
+	}
+
+	if store.userID != "u1" || store.secret != "s1" {
+		t.Fatalf("MemorySessionStore after Save = (%q, %q), want (%q, %q)", store.userID, store.secret, "u1", "s1")
+	}
+
+	restored, err := NewClientWithStoreAndOptions(store, ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClientWithStoreAndOptions() error = %v", err)
+	}
+
+	loggedIn, _ := restored.GetStatus()
+	if !loggedIn {
+		t.Fatalf("GetStatus() loggedIn = false, want true after restoring a saved session")
+	}
+}
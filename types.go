@@ -57,6 +57,10 @@ type Message struct {
 	Acknowledged convertibleBoolean `json:"acked"`
 	ReceiptCode  string             `json:"receipt"`
 	ContainsHTML convertibleBoolean `json:"html"`
+
+	// client is set by GetMessages/GetMessagesContext so Acknowledge can be
+	// called directly on a Message without threading the Client through.
+	client *Client `json:"-"`
 }
 
 // Taken from https://stackoverflow.com/questions/30856454/how-to-unmarshall-both-0-and-false-as-bool-from-json
@@ -74,12 +78,8 @@ func (bit *convertibleBoolean) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-type ErrorFrameError struct{}
-
-func (e *ErrorFrameError) Error() string {
-	return "Received error frame"
-}
-
+// Missing2FAError is returned by Login when the account requires two-factor
+// authentication. Call LoginWith2FA with the user's TOTP code to complete the login.
 type Missing2FAError struct{}
 
 func (e *Missing2FAError) Error() string {
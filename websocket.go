@@ -1,6 +1,7 @@
 package poclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -14,36 +15,75 @@ import (
 // If no keep-alive packet is received for one minute, the function exits with a timeout error (net.Error).
 // Note: This function clears all notifications after receiving them, so you should pull messages
 // from the Messages channel and save them if you want to keep them
+//
+// For a version that owns its own reconnect loop with backoff and exposes
+// typed handlers (in addition to the Messages channel), see Run.
 func (p *Client) ListenForNotifications() error {
 	if !p.loggedIn {
-		return errors.New("Not logged in")
+		return ErrNotLoggedIn
 	}
 	if !p.registered {
-		return errors.New("Device not registered")
+		return ErrDeviceNotRegistered
 	}
 
+	for {
+		if err := p.connectAndDispatch(context.Background(), p.handleNotification); err != nil {
+			return err
+		}
+		// a nil error means the API sent a reload frame; reconnect and keep listening
+	}
+}
+
+// CloseWebsocket forcefully closes a open websocket connection, if one exists
+// This also causes a running ListenForNotifications to return an error,
+// which you can use to reconnect
+func (p *Client) CloseWebsocket() {
+	if p.wsConn != nil {
+		p.wsConn.Close()
+		p.wsConn = nil
+	}
+}
+
+// connectAndDispatch dials the websocket, performs the login handshake, and
+// reads frames until dispatch reports a reconnect, the connection drops, or
+// ctx is cancelled. It returns nil only when dispatch requests a reconnect.
+func (p *Client) connectAndDispatch(ctx context.Context, dispatch func(message string) (reconnect bool, err error)) error {
 	u := url.URL{Scheme: "wss", Host: "client.pushover.net", Path: "/push"}
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
 	p.wsConn = conn // store reference to connection
+	defer func() { p.wsConn = nil }()
+
+	// gorilla's ReadMessage only respects SetReadDeadline, not ctx cancellation,
+	// so close the connection ourselves once ctx is done.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
 
 	if err := conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("login:%s:%s\n", p.device.ID, p.user.Secret))); err != nil {
 		return err
 	}
 
-	reconnect := false
-
-	for !reconnect {
+	for {
 		// time out after no keep-alive has been received for one minute
 		conn.SetReadDeadline(time.Now().Add(1 * time.Minute))
 
 		msgType, msgBytes, err := conn.ReadMessage()
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return err
 		}
 
@@ -51,52 +91,66 @@ func (p *Client) ListenForNotifications() error {
 			continue
 		}
 
-		if reconnect, err = p.handleNotification(string(msgBytes)); err != nil {
+		reconnect, err := dispatch(string(msgBytes))
+		if err != nil {
 			return err
 		}
+		if reconnect {
+			return nil
+		}
 	}
-
-	return p.ListenForNotifications() // reconnect
 }
 
-// CloseWebsocket forcefully closes a open websocket connection, if one exists
-// This also causes a running ListenForNotifications to return an error,
-// which you can use to reconnect
-func (p *Client) CloseWebsocket() {
-	if p.wsConn != nil {
-		p.wsConn.Close()
-		p.wsConn = nil
-	}
+func (p *Client) handleNotification(message string) (reconnect bool, err error) {
+	reconnect, _, err = p.dispatchNotification(message)
+	return reconnect, err
 }
 
-func (p Client) handleNotification(message string) (reconnect bool, err error) {
+// dispatchNotification interprets a single websocket frame, invoking any
+// handlers registered via OnMessage/OnKeepAlive/OnReload, and reports whether
+// a keep-alive was received (used by Run to reset its backoff) alongside the
+// usual reconnect/error results.
+//
+// dispatchNotification has a pointer receiver so that Messages it fetches via
+// GetMessages keep a reference to the live Client, not a frozen copy; see
+// GetMessagesContext.
+func (p *Client) dispatchNotification(message string) (reconnect bool, keepAlive bool, err error) {
 	switch message {
 	// Keep-alive packet, no response needed.
 	case "#":
-		return false, nil
+		if p.onKeepAlive != nil {
+			p.onKeepAlive()
+		}
+		return false, true, nil
 
 	// A new message has arrived; you should perform a sync.
 	case "!":
 		messages, err := p.GetMessages()
 		if err != nil {
-			return false, err
+			return false, false, err
 		}
 
 		for _, msg := range messages {
 			p.Messages <- msg //send messages into message channel
+			if p.onMessage != nil {
+				p.onMessage(msg)
+			}
 		}
 
-		return false, p.DeleteOldMessages(messages)
+		return false, false, p.DeleteOldMessages(messages)
 
 	// Reload request; you should drop your connection and re-connect.
 	case "R":
-		return true, nil
+		if p.onReload != nil {
+			p.onReload()
+		}
+		return true, false, nil
 
 	// Error; a permanent problem occurred and you should not automatically re-connect. Prompt the user to login again or re-enable the device.
 	case "E":
-		return false, &ErrorFrameError{}
+		return false, false, ErrDeviceDisabled
 
 	default:
-		return false, errors.New("Unexpected message received from API")
+		return false, false, errors.New("Unexpected message received from API")
 	}
 }